@@ -0,0 +1,16 @@
+package collectors
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// customCollector runs activeRuleSet, the operator-supplied mapping
+// loaded via "-config.file" (see mapping.go). It's how new "_node/stats"
+// fields can be turned into metrics without a code change or a new
+// subcollector.
+type customCollector struct{}
+
+func (customCollector) Name() string { return "custom" }
+
+func (customCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	activeRuleSet.Collect(*stats, ch)
+	return nil
+}
@@ -0,0 +1,64 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	eventsIn = prometheus.NewDesc(
+		"logstash_events_in",
+		"Number of logstash input events.",
+		[]string{"pipeline"}, nil,
+	)
+	eventsOut = prometheus.NewDesc(
+		"logstash_events_out",
+		"Number of logstash output events.",
+		[]string{"pipeline"}, nil,
+	)
+	eventsFiltered = prometheus.NewDesc(
+		"logstash_events_filtered",
+		"Number of logstash filtered events.",
+		[]string{"pipeline"}, nil,
+	)
+)
+
+// Event mirrors the "events" object found both at the top level of
+// "_node/stats" and inside each entry of "pipelines".
+type Event struct {
+	In       int `json:"in"`
+	Out      int `json:"out"`
+	Filtered int `json:"filtered"`
+}
+
+// eventsCollector reports the aggregate events counters at the root of
+// "_node/stats" (pipeline="") and, per pipeline, the same counters scoped
+// to that pipeline.
+type eventsCollector struct{}
+
+func (eventsCollector) Name() string { return "events" }
+
+func (eventsCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	if tree, ok := (*stats)["events"]; ok {
+		if err := emitEvents(tree, "", ch); err != nil {
+			return err
+		}
+	}
+	return forEachPipeline(stats, func(name string, pipeline map[string]interface{}) error {
+		tree, ok := pipeline["events"]
+		if !ok {
+			return nil
+		}
+		return emitEvents(tree, name, ch)
+	})
+}
+
+func emitEvents(data interface{}, pipeline string, ch chan<- prometheus.Metric) error {
+	event := &Event{}
+	if err := decodeInto(data, event); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(eventsIn, prometheus.GaugeValue, float64(event.In), pipeline)
+	ch <- prometheus.MustNewConstMetric(eventsOut, prometheus.GaugeValue, float64(event.Out), pipeline)
+	ch <- prometheus.MustNewConstMetric(eventsFiltered, prometheus.GaugeValue, float64(event.Filtered), pipeline)
+	return nil
+}
@@ -0,0 +1,68 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	flowInputThroughput = prometheus.NewDesc(
+		"logstash_flow_input_throughput",
+		"Throughput of logstash event input.",
+		[]string{"pipeline"}, nil,
+	)
+	flowFilterThroughput = prometheus.NewDesc(
+		"logstash_flow_filter_throughput",
+		"Throughput of logstash event filter.",
+		[]string{"pipeline"}, nil,
+	)
+	flowOutputThroughput = prometheus.NewDesc(
+		"logstash_flow_output_throughput",
+		"Throughput of logstash event output.",
+		[]string{"pipeline"}, nil,
+	)
+)
+
+// Flow mirrors the "flow" object found both at the top level of
+// "_node/stats" and inside each entry of "pipelines".
+type Flow struct {
+	InputThroughput  Throughput `json:"input_throughput"`
+	OutputThroughput Throughput `json:"output_throughput"`
+	FilterThroughput Throughput `json:"filter_throughput"`
+}
+
+type Throughput struct {
+	Current float64 `json:"current"`
+}
+
+// flowCollector reports the aggregate flow throughput at the root of
+// "_node/stats" (pipeline="") and, per pipeline, the same throughput
+// scoped to that pipeline.
+type flowCollector struct{}
+
+func (flowCollector) Name() string { return "flow" }
+
+func (flowCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	if tree, ok := (*stats)["flow"]; ok {
+		if err := emitFlow(tree, "", ch); err != nil {
+			return err
+		}
+	}
+	return forEachPipeline(stats, func(name string, pipeline map[string]interface{}) error {
+		tree, ok := pipeline["flow"]
+		if !ok {
+			return nil
+		}
+		return emitFlow(tree, name, ch)
+	})
+}
+
+func emitFlow(data interface{}, pipeline string, ch chan<- prometheus.Metric) error {
+	flow := &Flow{}
+	if err := decodeInto(data, flow); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(flowInputThroughput, prometheus.GaugeValue, flow.InputThroughput.Current, pipeline)
+	ch <- prometheus.MustNewConstMetric(flowFilterThroughput, prometheus.GaugeValue, flow.FilterThroughput.Current, pipeline)
+	ch <- prometheus.MustNewConstMetric(flowOutputThroughput, prometheus.GaugeValue, flow.OutputThroughput.Current, pipeline)
+	return nil
+}
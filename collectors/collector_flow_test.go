@@ -0,0 +1,65 @@
+package collectors
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectByDesc drains an already-closed metric channel into dto.Metric
+// values keyed by the descriptor's String() form, mirroring collectLogstash
+// in logstash_collector_test.go. Metrics sharing a descriptor overwrite one
+// another, so callers expect one metric per descriptor.
+func collectByDesc(t *testing.T, ch <-chan prometheus.Metric) map[string]*dto.Metric {
+	t.Helper()
+	out := make(map[string]*dto.Metric)
+	for m := range ch {
+		pb := &dto.Metric{}
+		if err := m.Write(pb); err != nil {
+			t.Fatalf("writing metric: %v", err)
+		}
+		out[m.Desc().String()] = pb
+	}
+	return out
+}
+
+// TestFlowCollectorThroughputRouting pins down that each Flow field routes
+// to the descriptor matching its own name - a prior version fed
+// OutputThroughput into flowFilterThroughput and vice versa, and that bug
+// produced no error or panic, just silently mislabeled values.
+func TestFlowCollectorThroughputRouting(t *testing.T) {
+	stats := Stats{
+		"flow": map[string]interface{}{
+			"input_throughput":  map[string]interface{}{"current": float64(1)},
+			"filter_throughput": map[string]interface{}{"current": float64(2)},
+			"output_throughput": map[string]interface{}{"current": float64(3)},
+		},
+	}
+
+	ch := make(chan prometheus.Metric, 8)
+	if err := (flowCollector{}).Update(&stats, ch); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	close(ch)
+
+	values := collectByDesc(t, ch)
+	cases := []struct {
+		desc *prometheus.Desc
+		want float64
+	}{
+		{flowInputThroughput, 1},
+		{flowFilterThroughput, 2},
+		{flowOutputThroughput, 3},
+	}
+	for _, c := range cases {
+		m, ok := values[c.desc.String()]
+		if !ok {
+			t.Fatalf("missing metric for %s", c.desc.String())
+		}
+		if got := m.GetGauge().GetValue(); got != c.want {
+			t.Errorf("%s = %v, want %v", c.desc.String(), got, c.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	jvmHeapUsedBytes = prometheus.NewDesc(
+		"logstash_jvm_heap_used_bytes",
+		"Current heap memory used by the JVM.",
+		nil, nil,
+	)
+	jvmHeapCommittedBytes = prometheus.NewDesc(
+		"logstash_jvm_heap_committed_bytes",
+		"Heap memory committed for use by the JVM.",
+		nil, nil,
+	)
+	jvmHeapMaxBytes = prometheus.NewDesc(
+		"logstash_jvm_heap_max_bytes",
+		"Maximum heap memory available to the JVM.",
+		nil, nil,
+	)
+	jvmNonHeapUsedBytes = prometheus.NewDesc(
+		"logstash_jvm_non_heap_used_bytes",
+		"Current non-heap memory used by the JVM.",
+		nil, nil,
+	)
+	jvmNonHeapCommittedBytes = prometheus.NewDesc(
+		"logstash_jvm_non_heap_committed_bytes",
+		"Non-heap memory committed for use by the JVM.",
+		nil, nil,
+	)
+	jvmThreadsCount = prometheus.NewDesc(
+		"logstash_jvm_threads_count",
+		"Number of live threads in the JVM.",
+		nil, nil,
+	)
+	jvmUptimeSeconds = prometheus.NewDesc(
+		"logstash_jvm_uptime_seconds",
+		"Uptime of the JVM.",
+		nil, nil,
+	)
+	jvmGCCollectionCount = prometheus.NewDesc(
+		"logstash_jvm_gc_collection_count",
+		"Number of garbage collections by a JVM GC collector.",
+		[]string{"collector"}, nil,
+	)
+	jvmGCCollectionDurationSeconds = prometheus.NewDesc(
+		"logstash_jvm_gc_collection_duration_seconds",
+		"Total time spent in garbage collection by a JVM GC collector.",
+		[]string{"collector"}, nil,
+	)
+)
+
+type JVM struct {
+	UptimeInMillis float64    `json:"uptime_in_millis"`
+	Mem            JVMMem     `json:"mem"`
+	Threads        JVMThreads `json:"threads"`
+	GC             JVMGC      `json:"gc"`
+}
+
+type JVMMem struct {
+	HeapUsedInBytes         float64 `json:"heap_used_in_bytes"`
+	HeapCommittedInBytes    float64 `json:"heap_committed_in_bytes"`
+	HeapMaxInBytes          float64 `json:"heap_max_in_bytes"`
+	NonHeapUsedInBytes      float64 `json:"non_heap_used_in_bytes"`
+	NonHeapCommittedInBytes float64 `json:"non_heap_committed_in_bytes"`
+}
+
+type JVMThreads struct {
+	Count int `json:"count"`
+}
+
+type JVMGC struct {
+	Collectors map[string]JVMGCCollector `json:"collectors"`
+}
+
+type JVMGCCollector struct {
+	CollectionCount        int     `json:"collection_count"`
+	CollectionTimeInMillis float64 `json:"collection_time_in_millis"`
+}
+
+// jvmCollector reports heap/non-heap memory, thread count, uptime and
+// garbage collector statistics for the Logstash JVM.
+type jvmCollector struct{}
+
+func (jvmCollector) Name() string { return "jvm" }
+
+func (jvmCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	tree, ok := (*stats)["jvm"]
+	if !ok {
+		return nil
+	}
+	jvm := &JVM{}
+	if err := decodeInto(tree, jvm); err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(jvmHeapUsedBytes, prometheus.GaugeValue, jvm.Mem.HeapUsedInBytes)
+	ch <- prometheus.MustNewConstMetric(jvmHeapCommittedBytes, prometheus.GaugeValue, jvm.Mem.HeapCommittedInBytes)
+	ch <- prometheus.MustNewConstMetric(jvmHeapMaxBytes, prometheus.GaugeValue, jvm.Mem.HeapMaxInBytes)
+	ch <- prometheus.MustNewConstMetric(jvmNonHeapUsedBytes, prometheus.GaugeValue, jvm.Mem.NonHeapUsedInBytes)
+	ch <- prometheus.MustNewConstMetric(jvmNonHeapCommittedBytes, prometheus.GaugeValue, jvm.Mem.NonHeapCommittedInBytes)
+	ch <- prometheus.MustNewConstMetric(jvmThreadsCount, prometheus.GaugeValue, float64(jvm.Threads.Count))
+	ch <- prometheus.MustNewConstMetric(jvmUptimeSeconds, prometheus.GaugeValue, jvm.UptimeInMillis/1000)
+
+	for name, collector := range jvm.GC.Collectors {
+		ch <- prometheus.MustNewConstMetric(jvmGCCollectionCount, prometheus.CounterValue, float64(collector.CollectionCount), name)
+		ch <- prometheus.MustNewConstMetric(jvmGCCollectionDurationSeconds, prometheus.CounterValue, collector.CollectionTimeInMillis/1000, name)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	osCPUPercent = prometheus.NewDesc(
+		"logstash_os_cpu_percent",
+		"Percentage of CPU time used across the host operating system.",
+		nil, nil,
+	)
+	osLoadAverage = prometheus.NewDesc(
+		"logstash_os_load_average",
+		"System load average.",
+		[]string{"period"}, nil,
+	)
+)
+
+type OS struct {
+	CPU OSCPU `json:"cpu"`
+}
+
+type OSCPU struct {
+	Percent     float64            `json:"percent"`
+	LoadAverage map[string]float64 `json:"load_average"`
+}
+
+// osCollector reports host-level CPU usage and load average, as seen by
+// the Logstash process.
+type osCollector struct{}
+
+func (osCollector) Name() string { return "os" }
+
+func (osCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	tree, ok := (*stats)["os"]
+	if !ok {
+		return nil
+	}
+	os := &OS{}
+	if err := decodeInto(tree, os); err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(osCPUPercent, prometheus.GaugeValue, os.CPU.Percent)
+	for period, value := range os.CPU.LoadAverage {
+		ch <- prometheus.MustNewConstMetric(osLoadAverage, prometheus.GaugeValue, value, period)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pluginEventsIn = prometheus.NewDesc(
+		"logstash_plugin_events_in",
+		"Number of events received by the plugin.",
+		[]string{"pipeline", "plugin_type", "plugin_name", "plugin_id"}, nil,
+	)
+	pluginEventsOut = prometheus.NewDesc(
+		"logstash_plugin_events_out",
+		"Number of events emitted by the plugin.",
+		[]string{"pipeline", "plugin_type", "plugin_name", "plugin_id"}, nil,
+	)
+	pluginEventsDurationSeconds = prometheus.NewDesc(
+		"logstash_plugin_events_duration_seconds",
+		"Time spent by the plugin processing events.",
+		[]string{"pipeline", "plugin_type", "plugin_name", "plugin_id"}, nil,
+	)
+)
+
+type PluginEvents struct {
+	In               int     `json:"in"`
+	Out              int     `json:"out"`
+	DurationInMillis float64 `json:"duration_in_millis"`
+}
+
+type Plugin struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Events PluginEvents `json:"events"`
+}
+
+type Plugins struct {
+	Inputs  []Plugin `json:"inputs"`
+	Filters []Plugin `json:"filters"`
+	Outputs []Plugin `json:"outputs"`
+	Codecs  []Plugin `json:"codecs"`
+}
+
+// pipelinesCollector reports per-plugin metrics for every pipeline. It's
+// kept separate from eventsCollector/flowCollector because a plugin's
+// labels (id, name) live in sibling keys next to its values rather than
+// in the path to them, so it needs its own decoding pass.
+type pipelinesCollector struct{}
+
+func (pipelinesCollector) Name() string { return "pipelines" }
+
+func (pipelinesCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	return forEachPipeline(stats, func(name string, pipeline map[string]interface{}) error {
+		tree, ok := pipeline["plugins"]
+		if !ok {
+			return nil
+		}
+		return emitPlugins(name, tree, ch)
+	})
+}
+
+func emitPlugins(pipeline string, data interface{}, ch chan<- prometheus.Metric) error {
+	plugins := &Plugins{}
+	if err := decodeInto(data, plugins); err != nil {
+		return err
+	}
+	emitPluginEvents(pipeline, "input", plugins.Inputs, ch)
+	emitPluginEvents(pipeline, "filter", plugins.Filters, ch)
+	emitPluginEvents(pipeline, "output", plugins.Outputs, ch)
+	emitPluginEvents(pipeline, "codec", plugins.Codecs, ch)
+	return nil
+}
+
+func emitPluginEvents(pipeline, pluginType string, plugins []Plugin, ch chan<- prometheus.Metric) {
+	for _, plugin := range plugins {
+		ch <- prometheus.MustNewConstMetric(pluginEventsIn, prometheus.CounterValue, float64(plugin.Events.In), pipeline, pluginType, plugin.Name, plugin.ID)
+		ch <- prometheus.MustNewConstMetric(pluginEventsOut, prometheus.CounterValue, float64(plugin.Events.Out), pipeline, pluginType, plugin.Name, plugin.ID)
+		ch <- prometheus.MustNewConstMetric(pluginEventsDurationSeconds, prometheus.CounterValue, plugin.Events.DurationInMillis/1000, pipeline, pluginType, plugin.Name, plugin.ID)
+	}
+}
@@ -0,0 +1,66 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	processCPUPercent = prometheus.NewDesc(
+		"logstash_process_cpu_percent",
+		"Percentage of CPU time spent by the Logstash process.",
+		nil, nil,
+	)
+	processMemVirtualBytes = prometheus.NewDesc(
+		"logstash_process_mem_virtual_bytes",
+		"Virtual memory used by the Logstash process.",
+		nil, nil,
+	)
+	processOpenFileDescriptors = prometheus.NewDesc(
+		"logstash_process_open_file_descriptors",
+		"Number of file descriptors open by the Logstash process.",
+		nil, nil,
+	)
+	processMaxFileDescriptors = prometheus.NewDesc(
+		"logstash_process_max_file_descriptors",
+		"Maximum number of file descriptors available to the Logstash process.",
+		nil, nil,
+	)
+)
+
+type Process struct {
+	OpenFileDescriptors int        `json:"open_file_descriptors"`
+	MaxFileDescriptors  int        `json:"max_file_descriptors"`
+	CPU                 ProcessCPU `json:"cpu"`
+	Mem                 ProcessMem `json:"mem"`
+}
+
+type ProcessCPU struct {
+	Percent float64 `json:"percent"`
+}
+
+type ProcessMem struct {
+	TotalVirtualInBytes float64 `json:"total_virtual_in_bytes"`
+}
+
+// processCollector reports resource usage of the Logstash process itself:
+// CPU, virtual memory and file descriptors.
+type processCollector struct{}
+
+func (processCollector) Name() string { return "process" }
+
+func (processCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	tree, ok := (*stats)["process"]
+	if !ok {
+		return nil
+	}
+	process := &Process{}
+	if err := decodeInto(tree, process); err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(processCPUPercent, prometheus.GaugeValue, process.CPU.Percent)
+	ch <- prometheus.MustNewConstMetric(processMemVirtualBytes, prometheus.GaugeValue, process.Mem.TotalVirtualInBytes)
+	ch <- prometheus.MustNewConstMetric(processOpenFileDescriptors, prometheus.GaugeValue, float64(process.OpenFileDescriptors))
+	ch <- prometheus.MustNewConstMetric(processMaxFileDescriptors, prometheus.GaugeValue, float64(process.MaxFileDescriptors))
+	return nil
+}
@@ -0,0 +1,56 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pipelineQueueEvents = prometheus.NewDesc(
+		"logstash_pipeline_queue_events",
+		"Number of unread events currently in the pipeline's persistent queue.",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineQueueSizeBytes = prometheus.NewDesc(
+		"logstash_pipeline_queue_size_bytes",
+		"Current size in bytes of the pipeline's persistent queue.",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineQueueMaxSizeBytes = prometheus.NewDesc(
+		"logstash_pipeline_queue_max_size_bytes",
+		"Configured capacity in bytes of the pipeline's persistent queue.",
+		[]string{"pipeline"}, nil,
+	)
+)
+
+type Queue struct {
+	Events              int `json:"events"`
+	QueueSizeInBytes    int `json:"queue_size_in_bytes"`
+	MaxQueueSizeInBytes int `json:"max_queue_size_in_bytes"`
+}
+
+// queueCollector reports each pipeline's persistent-queue occupancy and
+// configured capacity.
+type queueCollector struct{}
+
+func (queueCollector) Name() string { return "queue" }
+
+func (queueCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	return forEachPipeline(stats, func(name string, pipeline map[string]interface{}) error {
+		tree, ok := pipeline["queue"]
+		if !ok {
+			return nil
+		}
+		return emitQueue(name, tree, ch)
+	})
+}
+
+func emitQueue(pipeline string, data interface{}, ch chan<- prometheus.Metric) error {
+	queue := &Queue{}
+	if err := decodeInto(data, queue); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(pipelineQueueEvents, prometheus.GaugeValue, float64(queue.Events), pipeline)
+	ch <- prometheus.MustNewConstMetric(pipelineQueueSizeBytes, prometheus.GaugeValue, float64(queue.QueueSizeInBytes), pipeline)
+	ch <- prometheus.MustNewConstMetric(pipelineQueueMaxSizeBytes, prometheus.GaugeValue, float64(queue.MaxQueueSizeInBytes), pipeline)
+	return nil
+}
@@ -0,0 +1,77 @@
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pipelineReloadsSuccesses = prometheus.NewDesc(
+		"logstash_pipeline_reloads_successes",
+		"Number of successful pipeline reloads.",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineReloadsFailures = prometheus.NewDesc(
+		"logstash_pipeline_reloads_failures",
+		"Number of failed pipeline reloads.",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineReloadsLastSuccessTimestamp = prometheus.NewDesc(
+		"logstash_pipeline_reloads_last_success_timestamp_seconds",
+		"Unix timestamp of the pipeline's last successful reload.",
+		[]string{"pipeline"}, nil,
+	)
+	pipelineReloadsLastFailureTimestamp = prometheus.NewDesc(
+		"logstash_pipeline_reloads_last_failure_timestamp_seconds",
+		"Unix timestamp of the pipeline's last failed reload.",
+		[]string{"pipeline"}, nil,
+	)
+)
+
+type Reloads struct {
+	Successes            int     `json:"successes"`
+	Failures             int     `json:"failures"`
+	LastSuccessTimestamp *string `json:"last_success_timestamp"`
+	LastFailureTimestamp *string `json:"last_failure_timestamp"`
+}
+
+// reloadsCollector reports each pipeline's config reload counters.
+type reloadsCollector struct{}
+
+func (reloadsCollector) Name() string { return "reloads" }
+
+func (reloadsCollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	return forEachPipeline(stats, func(name string, pipeline map[string]interface{}) error {
+		tree, ok := pipeline["reloads"]
+		if !ok {
+			return nil
+		}
+		return emitReloads(name, tree, ch)
+	})
+}
+
+func emitReloads(pipeline string, data interface{}, ch chan<- prometheus.Metric) error {
+	reloads := &Reloads{}
+	if err := decodeInto(data, reloads); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(pipelineReloadsSuccesses, prometheus.CounterValue, float64(reloads.Successes), pipeline)
+	ch <- prometheus.MustNewConstMetric(pipelineReloadsFailures, prometheus.CounterValue, float64(reloads.Failures), pipeline)
+	if ts, ok := parseReloadTimestamp(reloads.LastSuccessTimestamp); ok {
+		ch <- prometheus.MustNewConstMetric(pipelineReloadsLastSuccessTimestamp, prometheus.GaugeValue, ts, pipeline)
+	}
+	if ts, ok := parseReloadTimestamp(reloads.LastFailureTimestamp); ok {
+		ch <- prometheus.MustNewConstMetric(pipelineReloadsLastFailureTimestamp, prometheus.GaugeValue, ts, pipeline)
+	}
+	return nil
+}
+
+func parseReloadTimestamp(value *string) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	t, err := parseTime(*value)
+	if err != nil {
+		return 0, false
+	}
+	return float64(t.Unix()), true
+}
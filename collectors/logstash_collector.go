@@ -2,115 +2,173 @@ package collectors
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/prometheus/client_golang/prometheus"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Descriptors are cached at package scope so that logstashCollector stays
+// cheap to construct on every "/probe" request. The metrics for each
+// logical area of "_node/stats" (events, jvm, process, ...) live next to
+// their Subcollector implementation - see subcollector.go and the
+// collector_*.go files.
+var (
+	up = prometheus.NewDesc(
+		"up",
+		"Was the last query successful.",
+		nil, nil,
+	)
+	scrapeDuration = prometheus.NewDesc(
+		"logstash_scrape_duration_seconds",
+		"Time it took to scrape the target Logstash instance.",
+		nil, nil,
+	)
+	scrapeError = prometheus.NewDesc(
+		"logstash_scrape_error",
+		"Whether the last scrape of the target Logstash instance failed (1 for failed).",
+		nil, nil,
+	)
+	scrapesTotal = prometheus.NewDesc(
+		"logstash_exporter_scrapes_total",
+		"Total number of times the target Logstash instance was scraped.",
+		nil, nil,
+	)
+	scrapeErrorsTotal = prometheus.NewDesc(
+		"logstash_exporter_scrape_errors_total",
+		"Total number of scrape errors, by reason.",
+		[]string{"reason"}, nil,
+	)
+	lastScrapeDuration = prometheus.NewDesc(
+		"logstash_exporter_last_scrape_duration_seconds",
+		"Duration of the last scrape of the target Logstash instance.",
+		nil, nil,
+	)
+)
+
+// logstashCollector scrapes a single Logstash node's "/_node/stats" endpoint.
+// It is constructed fresh for every "/probe" request, so it must stay cheap
+// to create - all of its *prometheus.Desc values live at package scope.
 type logstashCollector struct {
-	nodeStatsUri         string
-	up                   *prometheus.Desc
-	eventsIn             *prometheus.Desc
-	eventsFiltered       *prometheus.Desc
-	eventsOut            *prometheus.Desc
-	flowInputThroughput  *prometheus.Desc
-	flowFilterThroughput *prometheus.Desc
-	flowOutputThroughput *prometheus.Desc
+	target       string
+	nodeStatsUri string
+}
+
+// targetScrapeStats accumulates the running totals behind
+// logstash_exporter_scrapes_total and logstash_exporter_scrape_errors_total
+// for one target. Since a logstashCollector is constructed fresh for every
+// "/probe" request, these counters have to live here - keyed by target and
+// shared across requests - rather than on the collector itself.
+type targetScrapeStats struct {
+	scrapes      uint64
+	errorsByType sync.Map // reason -> *uint64
+}
+
+func (s *targetScrapeStats) incScrapes() uint64 {
+	return atomic.AddUint64(&s.scrapes, 1)
+}
+
+func (s *targetScrapeStats) incErrors(reason string) uint64 {
+	counter, _ := s.errorsByType.LoadOrStore(reason, new(uint64))
+	return atomic.AddUint64(counter.(*uint64), 1)
+}
+
+// scrapeStats is keyed by the raw "/probe?target=" value and never evicted,
+// so its size is bounded only by the number of distinct targets ever probed
+// over the process's lifetime. That's expected to track Prometheus's own
+// service-discovery config for this exporter (a bounded, slowly-changing
+// set of Logstash hosts) rather than grow without bound; if this exporter
+// is ever exposed to untrusted/arbitrary "target" values, this map would
+// need an eviction policy.
+var (
+	scrapeStatsMu sync.Mutex
+	scrapeStats   = map[string]*targetScrapeStats{}
+)
+
+// scrapeStatsFor returns the persistent scrape counters for target, creating
+// them on first use.
+func scrapeStatsFor(target string) *targetScrapeStats {
+	scrapeStatsMu.Lock()
+	defer scrapeStatsMu.Unlock()
+	s, ok := scrapeStats[target]
+	if !ok {
+		s = &targetScrapeStats{}
+		scrapeStats[target] = s
+	}
+	return s
 }
 
 type Stats map[string]interface{}
 
-func NewLogstashCollector(host string) prometheus.Collector {
-	fqName := func(name string) string {
-		return "logstash_" + name
-	}
+func NewLogstashCollector(target string) prometheus.Collector {
 	return &logstashCollector{
-		nodeStatsUri: "http://" + host + "/_node/stats",
-		up: prometheus.NewDesc(
-			"up",
-			"Was the last query successful.",
-			nil, nil,
-		),
-		eventsIn: prometheus.NewDesc(
-			fqName("events_in"),
-			"Number of logstash input events.",
-			nil, nil,
-		),
-		eventsFiltered: prometheus.NewDesc(
-			fqName("events_filtered"),
-			"Number of logstash filtered events.",
-			nil, nil,
-		),
-		eventsOut: prometheus.NewDesc(
-			fqName("events_out"),
-			"Number of logstash output events.",
-			nil, nil,
-		),
-		flowInputThroughput: prometheus.NewDesc(
-			fqName("flow_input_throughput"),
-			"Throughput of logstash event input.",
-			nil, nil,
-		),
-		flowFilterThroughput: prometheus.NewDesc(
-			fqName("flow_filter_throughput"),
-			"Throughput of logstash event filter.",
-			nil, nil,
-		),
-		flowOutputThroughput: prometheus.NewDesc(
-			fqName("flow_output_throughput"),
-			"Throughput of logstash event output.",
-			nil, nil,
-		),
+		target:       target,
+		nodeStatsUri: "http://" + target + "/_node/stats",
 	}
-
 }
 
 func (c *logstashCollector) Collect(ch chan<- prometheus.Metric) {
+	start := time.Now()
+	stats, reason, err := c.fetchStats()
+	duration := time.Since(start).Seconds()
+	targetStats := scrapeStatsFor(c.target)
 
-	stats, err := c.fetchStats()
+	upValue := 1.0
 	if err != nil {
 		log.Println("ERROR:", err)
+		upValue = 0
+		errCount := targetStats.incErrors(reason)
+		ch <- prometheus.MustNewConstMetric(scrapeError, prometheus.GaugeValue, 1)
+		ch <- prometheus.MustNewConstMetric(scrapeErrorsTotal, prometheus.CounterValue, float64(errCount), reason)
 	} else {
-		c.collectMetrics(stats, ch)
+		collectSubcollectors(stats, ch)
+		ch <- prometheus.MustNewConstMetric(scrapeError, prometheus.GaugeValue, 0)
 	}
-	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(scrapeDuration, prometheus.GaugeValue, duration)
+	ch <- prometheus.MustNewConstMetric(up, prometheus.GaugeValue, upValue)
+	ch <- prometheus.MustNewConstMetric(scrapesTotal, prometheus.CounterValue, float64(targetStats.incScrapes()))
+	ch <- prometheus.MustNewConstMetric(lastScrapeDuration, prometheus.GaugeValue, duration)
 }
 
 func (c *logstashCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.up
-	ch <- c.eventsIn
-	ch <- c.eventsFiltered
-	ch <- c.eventsOut
-	ch <- c.flowInputThroughput
-	ch <- c.flowFilterThroughput
-	ch <- c.flowOutputThroughput
+	ch <- up
+	ch <- scrapeDuration
+	ch <- scrapeError
+	ch <- scrapesTotal
+	ch <- scrapeErrorsTotal
+	ch <- lastScrapeDuration
+	describeSubcollectors(ch)
 }
 
-func (c *logstashCollector) fetchStats() (*Stats, error) {
-	body, err := c.fetch(c.nodeStatsUri)
+// fetchStats fetches and decodes the target's "/_node/stats" endpoint. On
+// failure it also returns a reason ("connect", "http_status" or "decode")
+// for the logstash_exporter_scrape_errors_total counter.
+func (c *logstashCollector) fetchStats() (*Stats, string, error) {
+	body, reason, err := c.fetch(c.nodeStatsUri)
 	if err != nil {
-		return nil, err
+		return nil, reason, err
 	}
 
 	var stats Stats
-	err = json.Unmarshal(body, &stats)
-	if err != nil {
-		return nil, err
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return nil, "decode", err
 	}
 
-	return &stats, nil
+	return &stats, "", nil
 }
 
-func (c *logstashCollector) fetch(uri string) ([]byte, error) {
+func (c *logstashCollector) fetch(uri string) ([]byte, string, error) {
 	client := http.Client{
 		Timeout: time.Second * 3,
 	}
 
 	resp, err := client.Get(uri)
 	if err != nil {
-		return nil, err
+		return nil, "connect", err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -119,80 +177,13 @@ func (c *logstashCollector) fetch(uri string) ([]byte, error) {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, err
+		return nil, "http_status", fmt.Errorf("unexpected status %d from %s", resp.StatusCode, uri)
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "connect", err
 	}
 
-	return body, nil
-}
-
-func (c *logstashCollector) collectMetrics(stats *Stats, ch chan<- prometheus.Metric) {
-	if tree, ok := (*stats)["events"]; ok {
-		c.collectEvents(tree, prometheus.Labels{}, ch)
-	}
-	if tree, ok := (*stats)["flow"]; ok {
-		c.collectFlow(tree, prometheus.Labels{}, ch)
-	}
-}
-
-type Event struct {
-	In       int `json:"in"`
-	Out      int `json:"out"`
-	Filtered int `json:"filtered"`
-}
-
-type Flow struct {
-	InputThroughput  Throughput `json:"input_throughput"`
-	OutputThroughput Throughput `json:"output_throughput"`
-	FilterThroughput Throughput `json:"filter_throughput"`
-}
-
-type Throughput struct {
-	Current float64 `json:"current"`
-}
-
-func (c *logstashCollector) collectEvents(data interface{}, labels prometheus.Labels, ch chan<- prometheus.Metric) {
-	labelNames := make([]string, 0)
-	for k := range labels {
-		labelNames = append(labelNames, k)
-	}
-	body, err := json.Marshal(data)
-	if err != nil {
-		log.Println("ERROR:", err)
-		return
-	}
-	event := &Event{}
-	err = json.Unmarshal(body, event)
-	if err != nil {
-		log.Println("ERROR:", err)
-		return
-	}
-	ch <- prometheus.MustNewConstMetric(c.eventsIn, prometheus.GaugeValue, float64(event.In), labelNames...)
-	ch <- prometheus.MustNewConstMetric(c.eventsOut, prometheus.GaugeValue, float64(event.Out), labelNames...)
-	ch <- prometheus.MustNewConstMetric(c.eventsFiltered, prometheus.GaugeValue, float64(event.Filtered), labelNames...)
-}
-
-func (c *logstashCollector) collectFlow(data interface{}, labels prometheus.Labels, ch chan<- prometheus.Metric) {
-	labelNames := make([]string, 0)
-	for k := range labels {
-		labelNames = append(labelNames, k)
-	}
-	body, err := json.Marshal(data)
-	if err != nil {
-		log.Println("ERROR:", err)
-		return
-	}
-	flow := &Flow{}
-	err = json.Unmarshal(body, flow)
-	if err != nil {
-		log.Println("ERROR:", err)
-		return
-	}
-	ch <- prometheus.MustNewConstMetric(c.flowInputThroughput, prometheus.GaugeValue, flow.InputThroughput.Current, labelNames...)
-	ch <- prometheus.MustNewConstMetric(c.flowFilterThroughput, prometheus.GaugeValue, flow.OutputThroughput.Current, labelNames...)
-	ch <- prometheus.MustNewConstMetric(c.flowOutputThroughput, prometheus.GaugeValue, flow.FilterThroughput.Current, labelNames...)
+	return body, "", nil
 }
@@ -0,0 +1,137 @@
+package collectors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectLogstash drains Collect into dto.Metric values keyed by the
+// descriptor's String() form, the way /probe's per-request registry would.
+func collectLogstash(t *testing.T, c *logstashCollector) map[string]*dto.Metric {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 64)
+	c.Collect(ch)
+	close(ch)
+
+	out := make(map[string]*dto.Metric)
+	for m := range ch {
+		pb := &dto.Metric{}
+		if err := m.Write(pb); err != nil {
+			t.Fatalf("writing metric: %v", err)
+		}
+		out[m.Desc().String()] = pb
+	}
+	return out
+}
+
+func descFQName(t *testing.T, d *prometheus.Desc) string {
+	t.Helper()
+	// prometheus.Desc doesn't expose its name directly; match on the
+	// stable Desc.String() output used as the map key in collectLogstash
+	// via the same descriptor, so tests look the value up by that.
+	return d.String()
+}
+
+func TestCollectSuccessReportsUp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := NewLogstashCollector(srv.Listener.Addr().String()).(*logstashCollector)
+	metrics := collectLogstash(t, c)
+
+	upMetric, ok := metrics[descFQName(t, up)]
+	if !ok {
+		t.Fatalf("missing up metric")
+	}
+	if got := upMetric.GetGauge().GetValue(); got != 1 {
+		t.Errorf("up = %v, want 1 on success", got)
+	}
+	if got := metrics[descFQName(t, scrapeError)].GetGauge().GetValue(); got != 0 {
+		t.Errorf("logstash_scrape_error = %v, want 0 on success", got)
+	}
+}
+
+func TestCollectHTTPErrorReportsDown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := NewLogstashCollector(srv.Listener.Addr().String()).(*logstashCollector)
+	metrics := collectLogstash(t, c)
+
+	if got := metrics[descFQName(t, up)].GetGauge().GetValue(); got != 0 {
+		t.Errorf("up = %v, want 0 on non-200", got)
+	}
+	if got := metrics[descFQName(t, scrapeError)].GetGauge().GetValue(); got != 1 {
+		t.Errorf("logstash_scrape_error = %v, want 1 on non-200", got)
+	}
+	errs := metrics[descFQName(t, scrapeErrorsTotal)]
+	if errs == nil || errs.GetLabel()[0].GetValue() != "http_status" {
+		t.Errorf("expected scrape_errors_total reason=http_status, got %+v", errs)
+	}
+}
+
+func TestCollectDecodeErrorReportsDown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`))
+	}))
+	defer srv.Close()
+
+	c := NewLogstashCollector(srv.Listener.Addr().String()).(*logstashCollector)
+	metrics := collectLogstash(t, c)
+
+	if got := metrics[descFQName(t, up)].GetGauge().GetValue(); got != 0 {
+		t.Errorf("up = %v, want 0 on decode error", got)
+	}
+	errs := metrics[descFQName(t, scrapeErrorsTotal)]
+	if errs == nil || errs.GetLabel()[0].GetValue() != "decode" {
+		t.Errorf("expected scrape_errors_total reason=decode, got %+v", errs)
+	}
+}
+
+func TestCollectConnectErrorReportsDown(t *testing.T) {
+	// A target nothing listens on triggers the "connect" failure path.
+	c := NewLogstashCollector("127.0.0.1:1").(*logstashCollector)
+	metrics := collectLogstash(t, c)
+
+	if got := metrics[descFQName(t, up)].GetGauge().GetValue(); got != 0 {
+		t.Errorf("up = %v, want 0 on connect error", got)
+	}
+	errs := metrics[descFQName(t, scrapeErrorsTotal)]
+	if errs == nil || errs.GetLabel()[0].GetValue() != "connect" {
+		t.Errorf("expected scrape_errors_total reason=connect, got %+v", errs)
+	}
+}
+
+func TestCollectScrapesTotalPersistsAcrossRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	target := srv.Listener.Addr().String()
+
+	// Each "/probe" request builds a fresh collector for the same target,
+	// so scrapesTotal must accumulate via the package-level scrapeStats
+	// map rather than reset to a constant every time.
+	var last float64
+	for i := 1; i <= 3; i++ {
+		c := NewLogstashCollector(target).(*logstashCollector)
+		metrics := collectLogstash(t, c)
+		got := metrics[descFQName(t, scrapesTotal)].GetCounter().GetValue()
+		if got != float64(i) {
+			t.Errorf("scrape %d: logstash_exporter_scrapes_total = %v, want %v", i, got, i)
+		}
+		last = got
+	}
+	if last != 3 {
+		t.Fatalf("expected 3 cumulative scrapes, got %v", last)
+	}
+}
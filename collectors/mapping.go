@@ -0,0 +1,180 @@
+package collectors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule declares how a single leaf value of the Logstash "_node/stats" JSON
+// tree is turned into a Prometheus metric. Path is a dot-separated list of
+// JSON object keys; a segment of "*" matches any key at that level, and
+// the matched key is made available to Labels as "$1", "$2", ... in the
+// order the wildcards appear in Path. A Labels value that isn't of the
+// form "$N" is used as a literal label value.
+type Rule struct {
+	Path   string            `yaml:"path"`
+	Name   string            `yaml:"name"`
+	Type   string            `yaml:"type"`
+	Help   string            `yaml:"help"`
+	Labels map[string]string `yaml:"labels"`
+
+	segments  []string
+	labelKeys []string
+	valueType prometheus.ValueType
+	desc      *prometheus.Desc
+}
+
+// RuleSet is a compiled mapping configuration, ready to be walked against a
+// decoded Stats tree.
+type RuleSet struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadRuleSet reads and compiles a YAML mapping file such as the one
+// accepted via the "-config.file" flag.
+func LoadRuleSet(path string) (*RuleSet, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRuleSet(body)
+}
+
+func parseRuleSet(body []byte) (*RuleSet, error) {
+	rs := &RuleSet{}
+	if err := yaml.Unmarshal(body, rs); err != nil {
+		return nil, err
+	}
+	if err := rs.compile(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (rs *RuleSet) compile() error {
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Path == "" || r.Name == "" {
+			return fmt.Errorf("mapping rule %d: path and name are required", i)
+		}
+		r.segments = strings.Split(r.Path, ".")
+
+		switch r.Type {
+		case "counter":
+			r.valueType = prometheus.CounterValue
+		case "gauge", "":
+			r.valueType = prometheus.GaugeValue
+		default:
+			return fmt.Errorf("mapping rule %q: unknown type %q", r.Name, r.Type)
+		}
+
+		r.labelKeys = make([]string, 0, len(r.Labels))
+		for key := range r.Labels {
+			r.labelKeys = append(r.labelKeys, key)
+		}
+		sort.Strings(r.labelKeys)
+
+		r.desc = prometheus.NewDesc(r.Name, r.Help, r.labelKeys, nil)
+	}
+	return nil
+}
+
+// match reports whether segments - the path walked down to a leaf value -
+// satisfies the rule's pattern, returning the wildcard captures in order.
+func (r *Rule) match(segments []string) ([]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	var captures []string
+	for i, want := range r.segments {
+		if want == "*" {
+			captures = append(captures, segments[i])
+			continue
+		}
+		if want != segments[i] {
+			return nil, false
+		}
+	}
+	return captures, true
+}
+
+// labelValues renders the rule's label templates against a set of wildcard
+// captures, in labelKeys order.
+func (r *Rule) labelValues(captures []string) []string {
+	values := make([]string, len(r.labelKeys))
+	for i, key := range r.labelKeys {
+		template := r.Labels[key]
+		if n, err := strconv.Atoi(strings.TrimPrefix(template, "$")); err == nil && strings.HasPrefix(template, "$") && n >= 1 && n <= len(captures) {
+			values[i] = captures[n-1]
+			continue
+		}
+		values[i] = template
+	}
+	return values
+}
+
+// Describe sends every compiled rule's descriptor down ch.
+func (rs *RuleSet) Describe(ch chan<- *prometheus.Desc) {
+	for i := range rs.Rules {
+		ch <- rs.Rules[i].desc
+	}
+}
+
+// Collect walks a decoded Stats tree and emits one metric per leaf value
+// that matches a rule.
+func (rs *RuleSet) Collect(stats Stats, ch chan<- prometheus.Metric) {
+	rs.walk(map[string]interface{}(stats), nil, ch)
+}
+
+func (rs *RuleSet) walk(data interface{}, path []string, ch chan<- prometheus.Metric) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := make([]string, len(path), len(path)+1)
+			copy(childPath, path)
+			rs.walk(child, append(childPath, key), ch)
+		}
+	case float64:
+		rs.emit(path, v, ch)
+	case bool:
+		value := 0.0
+		if v {
+			value = 1.0
+		}
+		rs.emit(path, value, ch)
+	}
+}
+
+func (rs *RuleSet) emit(path []string, value float64, ch chan<- prometheus.Metric) {
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		captures, ok := r.match(path)
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(r.desc, r.valueType, value, r.labelValues(captures)...)
+	}
+}
+
+// activeRuleSet backs the "custom" subcollector (see collector_custom.go),
+// shared by every logstashCollector. It starts out empty - events, flow,
+// pipeline queue/reloads and JVM/process/OS stats all now have their own
+// typed subcollectors - and is replaced by SetRuleSet when "-config.file"
+// points at an operator-supplied mapping for fields none of those cover.
+var activeRuleSet = &RuleSet{}
+
+// SetRuleSet installs rs as the mapping used by the "custom" subcollector
+// on all subsequently constructed collectors. rs's rules should only cover
+// fields the built-in subcollectors (events, flow, pipelines, jvm, process,
+// reloads, queue, os) don't already expose; a rule matching the same field
+// as one of those will register a second metric for it under a different
+// name rather than replacing or erroring.
+func SetRuleSet(rs *RuleSet) {
+	activeRuleSet = rs
+}
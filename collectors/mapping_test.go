@@ -0,0 +1,144 @@
+package collectors
+
+import (
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectMetrics drains a RuleSet.Collect call into dto.Metric values keyed
+// by the descriptor's String() form plus the metric's own text form, so each
+// distinct label set gets its own entry and tests can assert on exact
+// label/value pairs.
+func collectMetrics(t *testing.T, rs *RuleSet, stats Stats) map[string]*dto.Metric {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 64)
+	rs.Collect(stats, ch)
+	close(ch)
+
+	out := make(map[string]*dto.Metric)
+	for m := range ch {
+		pb := &dto.Metric{}
+		if err := m.Write(pb); err != nil {
+			t.Fatalf("writing metric: %v", err)
+		}
+		out[m.Desc().String()+"/"+pb.String()] = pb
+	}
+	return out
+}
+
+func mustCompile(t *testing.T, rs *RuleSet) *RuleSet {
+	t.Helper()
+	if err := rs.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	return rs
+}
+
+func findMetric(t *testing.T, metrics map[string]*dto.Metric, wantLabel, wantValue string) *dto.Metric {
+	t.Helper()
+	for _, m := range metrics {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == wantLabel && l.GetValue() == wantValue {
+				return m
+			}
+		}
+	}
+	t.Fatalf("no metric found with label %s=%s among %d metrics", wantLabel, wantValue, len(metrics))
+	return nil
+}
+
+func TestRuleMatchWildcardCapture(t *testing.T) {
+	rs := mustCompile(t, &RuleSet{Rules: []Rule{
+		{
+			Path:   "pipelines.*.events.in",
+			Name:   "logstash_pipeline_events_in",
+			Type:   "counter",
+			Labels: map[string]string{"pipeline": "$1"},
+		},
+	}})
+
+	stats := Stats{
+		"pipelines": map[string]interface{}{
+			"main": map[string]interface{}{
+				"events": map[string]interface{}{"in": float64(42)},
+			},
+			"side": map[string]interface{}{
+				"events": map[string]interface{}{"in": float64(7)},
+			},
+		},
+	}
+
+	metrics := collectMetrics(t, rs, stats)
+	if len(metrics) != 2 {
+		t.Fatalf("expected 2 metrics, got %d", len(metrics))
+	}
+
+	main := findMetric(t, metrics, "pipeline", "main")
+	if got := main.GetCounter().GetValue(); got != 42 {
+		t.Errorf("main pipeline events.in = %v, want 42", got)
+	}
+	side := findMetric(t, metrics, "pipeline", "side")
+	if got := side.GetCounter().GetValue(); got != 7 {
+		t.Errorf("side pipeline events.in = %v, want 7", got)
+	}
+}
+
+func TestRuleSetMultipleMatchingRules(t *testing.T) {
+	rs := mustCompile(t, &RuleSet{Rules: []Rule{
+		{Path: "jvm.mem.heap_used_in_bytes", Name: "logstash_jvm_heap_used_bytes", Type: "gauge"},
+		{Path: "jvm.mem.heap_used_in_bytes", Name: "logstash_jvm_heap_used_bytes_alias", Type: "gauge"},
+	}})
+
+	stats := Stats{
+		"jvm": map[string]interface{}{
+			"mem": map[string]interface{}{"heap_used_in_bytes": float64(123)},
+		},
+	}
+
+	metrics := collectMetrics(t, rs, stats)
+	if len(metrics) != 2 {
+		t.Fatalf("expected both rules to emit a metric for the same leaf, got %d", len(metrics))
+	}
+	for _, m := range metrics {
+		if got := m.GetGauge().GetValue(); got != 123 {
+			t.Errorf("heap_used_in_bytes = %v, want 123", got)
+		}
+	}
+}
+
+func TestRuleSetBoolLeaf(t *testing.T) {
+	rs := mustCompile(t, &RuleSet{Rules: []Rule{
+		{Path: "ok", Name: "logstash_ok", Type: "gauge"},
+	}})
+
+	metrics := collectMetrics(t, rs, Stats{"ok": true})
+	if len(metrics) != 1 {
+		t.Fatalf("expected 1 metric, got %d", len(metrics))
+	}
+	for _, m := range metrics {
+		if got := m.GetGauge().GetValue(); got != 1 {
+			t.Errorf("bool leaf true = %v, want 1", got)
+		}
+	}
+
+	metrics = collectMetrics(t, rs, Stats{"ok": false})
+	for _, m := range metrics {
+		if got := m.GetGauge().GetValue(); got != 0 {
+			t.Errorf("bool leaf false = %v, want 0", got)
+		}
+	}
+}
+
+func TestRuleSetNoMatch(t *testing.T) {
+	rs := mustCompile(t, &RuleSet{Rules: []Rule{
+		{Path: "jvm.mem.heap_used_in_bytes", Name: "logstash_jvm_heap_used_bytes", Type: "gauge"},
+	}})
+
+	metrics := collectMetrics(t, rs, Stats{"jvm": map[string]interface{}{"mem": map[string]interface{}{"heap_max_in_bytes": float64(1)}}})
+	if len(metrics) != 0 {
+		t.Fatalf("expected no metrics for an unmapped leaf, got %d", len(metrics))
+	}
+}
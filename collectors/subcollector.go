@@ -0,0 +1,120 @@
+package collectors
+
+import (
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Subcollector updates the metrics for one logical area of Logstash's
+// "_node/stats" response (events, jvm, process, ...). A subcollector that
+// fails doesn't prevent the others from reporting - its failure is itself
+// exposed as a metric.
+type Subcollector interface {
+	Name() string
+	Update(stats *Stats, ch chan<- prometheus.Metric) error
+}
+
+var (
+	collectorSuccess = prometheus.NewDesc(
+		"logstash_scrape_collector_success",
+		"Whether a collector succeeded.",
+		[]string{"collector"}, nil,
+	)
+	collectorDuration = prometheus.NewDesc(
+		"logstash_scrape_collector_duration_seconds",
+		"Duration of a collector's Update call.",
+		[]string{"collector"}, nil,
+	)
+)
+
+// subcollectors lists every subcollector the exporter knows about, in the
+// order they run. Each can be toggled independently with a
+// "-collector.<name>"/"-no-collector.<name>" flag pair - see main.go.
+var subcollectors = []Subcollector{
+	eventsCollector{},
+	flowCollector{},
+	pipelinesCollector{},
+	jvmCollector{},
+	processCollector{},
+	reloadsCollector{},
+	queueCollector{},
+	osCollector{},
+	customCollector{},
+}
+
+// enabledCollectors holds the current enable state for every subcollector
+// above, keyed by Name(). All default to enabled.
+var enabledCollectors = func() map[string]bool {
+	enabled := make(map[string]bool, len(subcollectors))
+	for _, sc := range subcollectors {
+		enabled[sc.Name()] = true
+	}
+	return enabled
+}()
+
+// SetCollectorEnabled toggles whether the named subcollector runs on
+// subsequent scrapes. Unknown names are ignored.
+func SetCollectorEnabled(name string, on bool) {
+	if _, ok := enabledCollectors[name]; ok {
+		enabledCollectors[name] = on
+	}
+}
+
+func collectSubcollectors(stats *Stats, ch chan<- prometheus.Metric) {
+	for _, sc := range subcollectors {
+		if !enabledCollectors[sc.Name()] {
+			continue
+		}
+
+		start := time.Now()
+		err := sc.Update(stats, ch)
+		duration := time.Since(start).Seconds()
+
+		success := 1.0
+		if err != nil {
+			log.Println("ERROR:", sc.Name(), "collector:", err)
+			success = 0
+		}
+		ch <- prometheus.MustNewConstMetric(collectorDuration, prometheus.GaugeValue, duration, sc.Name())
+		ch <- prometheus.MustNewConstMetric(collectorSuccess, prometheus.GaugeValue, success, sc.Name())
+	}
+}
+
+func describeSubcollectors(ch chan<- *prometheus.Desc) {
+	ch <- collectorSuccess
+	ch <- collectorDuration
+	ch <- eventsIn
+	ch <- eventsOut
+	ch <- eventsFiltered
+	ch <- flowInputThroughput
+	ch <- flowFilterThroughput
+	ch <- flowOutputThroughput
+	ch <- pluginEventsIn
+	ch <- pluginEventsOut
+	ch <- pluginEventsDurationSeconds
+	ch <- jvmHeapUsedBytes
+	ch <- jvmHeapCommittedBytes
+	ch <- jvmHeapMaxBytes
+	ch <- jvmNonHeapUsedBytes
+	ch <- jvmNonHeapCommittedBytes
+	ch <- jvmThreadsCount
+	ch <- jvmUptimeSeconds
+	ch <- jvmGCCollectionCount
+	ch <- jvmGCCollectionDurationSeconds
+	ch <- processCPUPercent
+	ch <- processMemVirtualBytes
+	ch <- processOpenFileDescriptors
+	ch <- processMaxFileDescriptors
+	ch <- pipelineReloadsSuccesses
+	ch <- pipelineReloadsFailures
+	ch <- pipelineReloadsLastSuccessTimestamp
+	ch <- pipelineReloadsLastFailureTimestamp
+	ch <- pipelineQueueEvents
+	ch <- pipelineQueueSizeBytes
+	ch <- pipelineQueueMaxSizeBytes
+	ch <- osCPUPercent
+	ch <- osLoadAverage
+	activeRuleSet.Describe(ch)
+}
@@ -0,0 +1,305 @@
+package collectors
+
+import (
+	"errors"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestJVMCollectorUpdate(t *testing.T) {
+	stats := Stats{
+		"jvm": map[string]interface{}{
+			"uptime_in_millis": float64(5000),
+			"mem": map[string]interface{}{
+				"heap_used_in_bytes":          float64(1),
+				"heap_committed_in_bytes":     float64(2),
+				"heap_max_in_bytes":           float64(3),
+				"non_heap_used_in_bytes":      float64(4),
+				"non_heap_committed_in_bytes": float64(5),
+			},
+			"threads": map[string]interface{}{"count": float64(7)},
+			"gc": map[string]interface{}{
+				"collectors": map[string]interface{}{
+					"young": map[string]interface{}{
+						"collection_count":          float64(10),
+						"collection_time_in_millis": float64(2000),
+					},
+				},
+			},
+		},
+	}
+
+	ch := make(chan prometheus.Metric, 16)
+	if err := (jvmCollector{}).Update(&stats, ch); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	close(ch)
+	metrics := collectByDesc(t, ch)
+
+	wantGauge := map[*prometheus.Desc]float64{
+		jvmHeapUsedBytes:         1,
+		jvmHeapCommittedBytes:    2,
+		jvmHeapMaxBytes:          3,
+		jvmNonHeapUsedBytes:      4,
+		jvmNonHeapCommittedBytes: 5,
+		jvmThreadsCount:          7,
+		jvmUptimeSeconds:         5,
+	}
+	for desc, want := range wantGauge {
+		m, ok := metrics[desc.String()]
+		if !ok {
+			t.Fatalf("missing metric for %s", desc.String())
+		}
+		if got := m.GetGauge().GetValue(); got != want {
+			t.Errorf("%s = %v, want %v", desc.String(), got, want)
+		}
+	}
+
+	gcCount, ok := metrics[jvmGCCollectionCount.String()]
+	if !ok || gcCount.GetCounter().GetValue() != 10 {
+		t.Errorf("jvm_gc_collection_count = %+v, want 10", gcCount)
+	}
+	gcDuration, ok := metrics[jvmGCCollectionDurationSeconds.String()]
+	if !ok || gcDuration.GetCounter().GetValue() != 2 {
+		t.Errorf("jvm_gc_collection_duration_seconds = %+v, want 2", gcDuration)
+	}
+}
+
+func TestProcessCollectorUpdate(t *testing.T) {
+	stats := Stats{
+		"process": map[string]interface{}{
+			"open_file_descriptors": float64(11),
+			"max_file_descriptors":  float64(100),
+			"cpu":                   map[string]interface{}{"percent": float64(42)},
+			"mem":                   map[string]interface{}{"total_virtual_in_bytes": float64(999)},
+		},
+	}
+
+	ch := make(chan prometheus.Metric, 16)
+	if err := (processCollector{}).Update(&stats, ch); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	close(ch)
+	metrics := collectByDesc(t, ch)
+
+	wantGauge := map[*prometheus.Desc]float64{
+		processCPUPercent:          42,
+		processMemVirtualBytes:     999,
+		processOpenFileDescriptors: 11,
+		processMaxFileDescriptors:  100,
+	}
+	for desc, want := range wantGauge {
+		m, ok := metrics[desc.String()]
+		if !ok {
+			t.Fatalf("missing metric for %s", desc.String())
+		}
+		if got := m.GetGauge().GetValue(); got != want {
+			t.Errorf("%s = %v, want %v", desc.String(), got, want)
+		}
+	}
+}
+
+func TestOSCollectorUpdate(t *testing.T) {
+	stats := Stats{
+		"os": map[string]interface{}{
+			"cpu": map[string]interface{}{
+				"percent":      float64(55),
+				"load_average": map[string]interface{}{"1m": float64(0.5)},
+			},
+		},
+	}
+
+	ch := make(chan prometheus.Metric, 16)
+	if err := (osCollector{}).Update(&stats, ch); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	close(ch)
+	metrics := collectByDesc(t, ch)
+
+	cpu, ok := metrics[osCPUPercent.String()]
+	if !ok || cpu.GetGauge().GetValue() != 55 {
+		t.Errorf("os_cpu_percent = %+v, want 55", cpu)
+	}
+	load := findMetric(t, metrics, "period", "1m")
+	if got := load.GetGauge().GetValue(); got != 0.5 {
+		t.Errorf("os_load_average{period=1m} = %v, want 0.5", got)
+	}
+}
+
+func TestQueueCollectorUpdate(t *testing.T) {
+	stats := Stats{
+		"pipelines": map[string]interface{}{
+			"main": map[string]interface{}{
+				"queue": map[string]interface{}{
+					"events":                  float64(3),
+					"queue_size_in_bytes":     float64(1024),
+					"max_queue_size_in_bytes": float64(4096),
+				},
+			},
+		},
+	}
+
+	ch := make(chan prometheus.Metric, 16)
+	if err := (queueCollector{}).Update(&stats, ch); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	close(ch)
+	metrics := collectByDesc(t, ch)
+
+	wantGauge := map[*prometheus.Desc]float64{
+		pipelineQueueEvents:       3,
+		pipelineQueueSizeBytes:    1024,
+		pipelineQueueMaxSizeBytes: 4096,
+	}
+	for desc, want := range wantGauge {
+		m, ok := metrics[desc.String()]
+		if !ok {
+			t.Fatalf("missing metric for %s", desc.String())
+		}
+		if got := m.GetGauge().GetValue(); got != want {
+			t.Errorf("%s = %v, want %v", desc.String(), got, want)
+		}
+		if got := m.GetLabel()[0].GetValue(); got != "main" {
+			t.Errorf("%s pipeline label = %q, want main", desc.String(), got)
+		}
+	}
+}
+
+func TestReloadsCollectorUpdate(t *testing.T) {
+	stats := Stats{
+		"pipelines": map[string]interface{}{
+			"main": map[string]interface{}{
+				"reloads": map[string]interface{}{
+					"successes":              float64(2),
+					"failures":               float64(1),
+					"last_success_timestamp": "2023-01-02T15:04:05.000Z",
+					"last_failure_timestamp": nil,
+				},
+			},
+		},
+	}
+
+	ch := make(chan prometheus.Metric, 16)
+	if err := (reloadsCollector{}).Update(&stats, ch); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	close(ch)
+	metrics := collectByDesc(t, ch)
+
+	if got := metrics[pipelineReloadsSuccesses.String()].GetCounter().GetValue(); got != 2 {
+		t.Errorf("pipeline_reloads_successes = %v, want 2", got)
+	}
+	if got := metrics[pipelineReloadsFailures.String()].GetCounter().GetValue(); got != 1 {
+		t.Errorf("pipeline_reloads_failures = %v, want 1", got)
+	}
+	wantTS, err := parseTime("2023-01-02T15:04:05.000Z")
+	if err != nil {
+		t.Fatalf("parseTime: %v", err)
+	}
+	if got := metrics[pipelineReloadsLastSuccessTimestamp.String()].GetGauge().GetValue(); got != float64(wantTS.Unix()) {
+		t.Errorf("pipeline_reloads_last_success_timestamp_seconds = %v, want %v", got, wantTS.Unix())
+	}
+	if _, ok := metrics[pipelineReloadsLastFailureTimestamp.String()]; ok {
+		t.Errorf("expected no last_failure_timestamp metric when the field is nil")
+	}
+}
+
+// fakeSubcollector is a test-only Subcollector used to exercise
+// collectSubcollectors' fan-out/isolation and enable/disable behavior
+// without depending on any real subcollector's data shape.
+type fakeSubcollector struct {
+	name  string
+	err   error
+	calls *int
+}
+
+func (f fakeSubcollector) Name() string { return f.name }
+
+func (f fakeSubcollector) Update(stats *Stats, ch chan<- prometheus.Metric) error {
+	if f.calls != nil {
+		*f.calls++
+	}
+	return f.err
+}
+
+func withTemporarySubcollectors(t *testing.T, scs []Subcollector, enabled map[string]bool) {
+	t.Helper()
+	origSubcollectors := subcollectors
+	origEnabled := enabledCollectors
+	t.Cleanup(func() {
+		subcollectors = origSubcollectors
+		enabledCollectors = origEnabled
+	})
+	subcollectors = scs
+	enabledCollectors = enabled
+}
+
+func collectorSuccessByName(t *testing.T, ch <-chan prometheus.Metric) map[string]float64 {
+	t.Helper()
+	success := map[string]float64{}
+	for m := range ch {
+		if m.Desc().String() != collectorSuccess.String() {
+			continue
+		}
+		pb := &dto.Metric{}
+		if err := m.Write(pb); err != nil {
+			t.Fatalf("writing metric: %v", err)
+		}
+		for _, l := range pb.GetLabel() {
+			if l.GetName() == "collector" {
+				success[l.GetValue()] = pb.GetGauge().GetValue()
+			}
+		}
+	}
+	return success
+}
+
+func TestCollectSubcollectorsIsolatesFailures(t *testing.T) {
+	withTemporarySubcollectors(t,
+		[]Subcollector{
+			fakeSubcollector{name: "ok"},
+			fakeSubcollector{name: "broken", err: errors.New("boom")},
+		},
+		map[string]bool{"ok": true, "broken": true},
+	)
+
+	ch := make(chan prometheus.Metric, 16)
+	stats := Stats{}
+	collectSubcollectors(&stats, ch)
+	close(ch)
+
+	success := collectorSuccessByName(t, ch)
+	if success["ok"] != 1 {
+		t.Errorf("collector_success{collector=ok} = %v, want 1", success["ok"])
+	}
+	if success["broken"] != 0 {
+		t.Errorf("collector_success{collector=broken} = %v, want 0 so a failing subcollector doesn't poison the scrape", success["broken"])
+	}
+}
+
+func TestSetCollectorEnabledSkipsDisabledSubcollector(t *testing.T) {
+	calls := 0
+	withTemporarySubcollectors(t,
+		[]Subcollector{fakeSubcollector{name: "counted", calls: &calls}},
+		map[string]bool{"counted": true},
+	)
+
+	stats := Stats{}
+	ch := make(chan prometheus.Metric, 16)
+	collectSubcollectors(&stats, ch)
+	close(ch)
+	if calls != 1 {
+		t.Fatalf("expected Update to be called once while enabled, got %d", calls)
+	}
+
+	SetCollectorEnabled("counted", false)
+	ch2 := make(chan prometheus.Metric, 16)
+	collectSubcollectors(&stats, ch2)
+	close(ch2)
+	if calls != 1 {
+		t.Errorf("expected Update not to run again once disabled, got %d calls", calls)
+	}
+}
@@ -0,0 +1,50 @@
+package collectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// decodeInto round-trips data (a subtree of a decoded Stats document)
+// through JSON to populate out, the pattern every subcollector uses to
+// turn an "interface{}" subtree into its typed struct.
+func decodeInto(data interface{}, out interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// parseTime parses the ISO8601 timestamps Logstash embeds in its stats
+// responses (e.g. "2023-01-02T15:04:05.000Z").
+func parseTime(value string) (time.Time, error) {
+	return time.Parse("2006-01-02T15:04:05.000Z", value)
+}
+
+// forEachPipeline calls fn once per pipeline found in stats["pipelines"].
+// A pipeline whose own data fn can't handle is logged and skipped so one
+// malformed pipeline doesn't take down its siblings.
+func forEachPipeline(stats *Stats, fn func(name string, pipeline map[string]interface{}) error) error {
+	tree, ok := (*stats)["pipelines"]
+	if !ok {
+		return nil
+	}
+	pipelines, ok := tree.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("pipelines is not an object")
+	}
+	for name, data := range pipelines {
+		pipeline, ok := data.(map[string]interface{})
+		if !ok {
+			log.Println("ERROR: pipeline", name, "is not an object")
+			continue
+		}
+		if err := fn(name, pipeline); err != nil {
+			log.Println("ERROR: pipeline", name, ":", err)
+		}
+	}
+	return nil
+}
@@ -1,17 +1,106 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"net/http"
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
+	promcollectors "github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/ydgo/prometheus-logstash-exporter/collectors"
-	"net/http"
 )
 
+var (
+	listenAddress = flag.String("web.listen-address", ":8080", "Address to listen on for web interface and telemetry.")
+	configFile    = flag.String("config.file", "", "Path to a YAML file mapping Logstash stats fields to metrics, used by the \"custom\" collector (optional). Rules should only cover fields the built-in collectors don't already expose, or the same stats field will be published under two metric names.")
+)
+
+// collectorNames lists every subcollector the exporter ships, in the
+// order their "-collector.<name>"/"-no-collector.<name>" flags are
+// registered. All default to enabled.
+var collectorNames = []string{"events", "flow", "pipelines", "jvm", "process", "reloads", "queue", "os", "custom"}
+
+// enableFlag implements flag.Value for a boolean that can be driven by
+// two differently-named flags sharing the same backing variable: a
+// positive "-collector.<name>" and a negative "-no-collector.<name>",
+// mirroring node_exporter's per-collector toggle convention.
+type enableFlag struct {
+	enabled *bool
+	negate  bool
+}
+
+func (f *enableFlag) String() string {
+	if f.enabled == nil {
+		return "true"
+	}
+	return strconv.FormatBool(*f.enabled != f.negate)
+}
+
+func (f *enableFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*f.enabled = v != f.negate
+	return nil
+}
+
+func (f *enableFlag) IsBoolFlag() bool { return true }
+
+func registerCollectorFlags() map[string]*bool {
+	enabled := make(map[string]*bool, len(collectorNames))
+	for _, name := range collectorNames {
+		on := true
+		enabled[name] = &on
+		flag.Var(&enableFlag{enabled: &on}, "collector."+name, "Enable the "+name+" collector (default true).")
+		flag.Var(&enableFlag{enabled: &on, negate: true}, "no-collector."+name, "Disable the "+name+" collector.")
+	}
+	return enabled
+}
+
+func probeHandler(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewLogstashCollector(target))
+
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
 func main() {
+	collectorEnabled := registerCollectorFlags()
+	flag.Parse()
+
+	for name, on := range collectorEnabled {
+		collectors.SetCollectorEnabled(name, *on)
+	}
+
+	if *configFile != "" {
+		ruleSet, err := collectors.LoadRuleSet(*configFile)
+		if err != nil {
+			log.Fatalf("error loading config.file %q: %v", *configFile, err)
+		}
+		collectors.SetRuleSet(ruleSet)
+	}
+
 	reg := prometheus.NewRegistry()
-	exporter := collectors.NewLogstashCollector("localhost:9600")
-	reg.MustRegister(exporter)
-	http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
-	_ = http.ListenAndServe(":8080", nil)
+	reg.MustRegister(promcollectors.NewGoCollector())
+	reg.MustRegister(promcollectors.NewProcessCollector(promcollectors.ProcessCollectorOpts{}))
+
+	handler := promhttp.HandlerFor(reg, promhttp.HandlerOpts{
+		ErrorLog:      log.Default(),
+		ErrorHandling: promhttp.ContinueOnError,
+		Registry:      reg,
+	})
+	http.Handle("/metrics", promhttp.InstrumentMetricHandler(reg, handler))
+	http.HandleFunc("/probe", probeHandler)
 
+	log.Printf("Listening on %s", *listenAddress)
+	log.Fatal(http.ListenAndServe(*listenAddress, nil))
 }